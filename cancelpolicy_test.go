@@ -0,0 +1,207 @@
+package syncgroup_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kurt212/syncgroup"
+	"github.com/kurt212/syncgroup/internal/testutil"
+)
+
+func TestCancelNeverDoesNotCancelOnError(t *testing.T) {
+	t.Parallel()
+
+	syncgrp, ctx := syncgroup.NewWithContext(context.Background())
+	syncgrp.SetCancelPolicy(syncgroup.CancelNever())
+
+	returnMyErr := MyError{"123"}
+
+	blockingStartedChan := make(chan struct{})
+	releaseChan := make(chan struct{})
+
+	syncgrp.GoCtx(func(_ context.Context) error {
+		close(blockingStartedChan)
+		<-releaseChan
+
+		return nil
+	})
+
+	syncgrp.GoCtx(func(_ context.Context) error {
+		return returnMyErr
+	})
+
+	<-blockingStartedChan
+	time.Sleep(10 * time.Millisecond)
+
+	testutil.True(t, ctx.Err() == nil, "CancelNever should not cancel the context while the round is still in progress")
+
+	close(releaseChan)
+
+	err := syncgrp.Wait()
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
+
+func TestCancelOnFirstPanicCancelsOnPanicNotOnError(t *testing.T) {
+	t.Parallel()
+
+	syncgrp, ctx := syncgroup.NewWithContext(context.Background())
+	syncgrp.SetCancelPolicy(syncgroup.CancelOnFirstPanic())
+
+	returnMyErr := MyError{"123"}
+
+	blockingStartedChan := make(chan struct{})
+	releaseChan := make(chan struct{})
+
+	syncgrp.GoCtx(func(_ context.Context) error {
+		close(blockingStartedChan)
+		<-releaseChan
+
+		return nil
+	})
+
+	syncgrp.GoCtx(func(_ context.Context) error {
+		return returnMyErr
+	})
+
+	<-blockingStartedChan
+	time.Sleep(10 * time.Millisecond)
+
+	testutil.True(t, ctx.Err() == nil, "CancelOnFirstPanic should not cancel the context on a plain error")
+
+	close(releaseChan)
+
+	_ = syncgrp.Wait()
+
+	syncgrp.Reset()
+
+	panicBlockingStartedChan := make(chan struct{})
+	panicReleaseChan := make(chan struct{})
+
+	var secondRoundCtx context.Context
+
+	syncgrp.GoCtx(func(ctx context.Context) error {
+		secondRoundCtx = ctx
+
+		close(panicBlockingStartedChan)
+		<-panicReleaseChan
+
+		return nil
+	})
+
+	syncgrp.GoCtx(func(_ context.Context) error {
+		panic("boom")
+	})
+
+	<-panicBlockingStartedChan
+	time.Sleep(10 * time.Millisecond)
+
+	testutil.True(t, secondRoundCtx.Err() != nil, "CancelOnFirstPanic should cancel the context on a panic")
+
+	close(panicReleaseChan)
+
+	_ = syncgrp.Wait()
+}
+
+func TestCancelOnErrorCountCancelsOnceThresholdReached(t *testing.T) {
+	t.Parallel()
+
+	syncgrp, ctx := syncgroup.NewWithContext(context.Background())
+	syncgrp.SetCancelPolicy(syncgroup.CancelOnErrorCount(2))
+
+	firstErr := MyError{"123"}
+	secondErr := MyError{"456"}
+
+	blockingStartedChan := make(chan struct{})
+	releaseChan := make(chan struct{})
+
+	syncgrp.GoCtx(func(_ context.Context) error {
+		close(blockingStartedChan)
+		<-releaseChan
+
+		return nil
+	})
+
+	syncgrp.GoCtx(func(_ context.Context) error {
+		return firstErr
+	})
+
+	<-blockingStartedChan
+	time.Sleep(10 * time.Millisecond)
+
+	testutil.True(t, ctx.Err() == nil, "context should not be canceled before the threshold is reached")
+
+	syncgrp.GoCtx(func(_ context.Context) error {
+		return secondErr
+	})
+
+	time.Sleep(10 * time.Millisecond)
+
+	testutil.True(t, ctx.Err() != nil, "context should be canceled once the error count threshold is reached")
+
+	close(releaseChan)
+
+	err := syncgrp.Wait()
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
+
+func TestTryGoCtxRunsTaskWithGroupContext(t *testing.T) {
+	t.Parallel()
+
+	syncgrp, ctx := syncgroup.NewWithContext(context.Background())
+
+	var observedCtx context.Context
+
+	ok := syncgrp.TryGoCtx(func(ctx context.Context) error {
+		observedCtx = ctx
+
+		return nil
+	})
+
+	testutil.True(t, ok, "TryGoCtx should report that the task was started")
+
+	err := syncgrp.Wait()
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	testutil.True(t, observedCtx == ctx, "TryGoCtx should pass the group's context to fnc")
+}
+
+func TestTryGoCtxReturnsFalseWhenLimitReached(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.New()
+	syncgrp.SetLimit(1)
+
+	startedChan := make(chan struct{})
+	releaseChan := make(chan struct{})
+
+	ok := syncgrp.TryGoCtx(func(_ context.Context) error {
+		close(startedChan)
+		<-releaseChan
+
+		return nil
+	})
+
+	testutil.True(t, ok, "first TryGoCtx call should start, since a slot is free")
+
+	<-startedChan
+
+	ok = syncgrp.TryGoCtx(func(_ context.Context) error {
+		return nil
+	})
+
+	testutil.True(t, !ok, "second TryGoCtx call should be rejected, since the limit is reached")
+
+	close(releaseChan)
+
+	err := syncgrp.Wait()
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package syncgroup
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// errGoexit marks a Do/TryDo call whose fnc terminated via runtime.Goexit rather than
+// returning or panicking. It is never propagated to sharers, since they did not call
+// runtime.Goexit themselves, matching the semantics of x/sync/singleflight.
+var errGoexit = errors.New("syncgroup: fnc called runtime.Goexit")
+
+// panicError wraps a value recovered from a panic together with the stack trace captured
+// at the point of recovery. Do/TryDo stores it as a call's error so that a panic in the
+// primary call can be distinguished from a plain error or a runtime.Goexit.
+type panicError struct {
+	value any
+	stack []byte
+}
+
+func (p *panicError) Error() string {
+	return fmt.Sprintf("%v\n\n%s", p.value, p.stack)
+}
+
+func (p *panicError) Unwrap() error {
+	err, ok := p.value.(error)
+	if !ok {
+		return nil
+	}
+
+	return err
+}
+
+// call tracks a single in-flight Do/TryDo invocation for a given key. workerPanic is set by
+// the primary call once, if fnc panicked, and then read - never mutated - by every sharer,
+// each of which reports its own clone extended with its own stack frame: the shared pointer
+// itself must stay read-only past that point, since multiple sharers read it concurrently.
+type call struct {
+	wg          sync.WaitGroup
+	err         error
+	workerPanic *WorkerPanic
+}
+
+// Do suppresses duplicate in-flight submissions with the same key: if a task with key is
+// already running or queued in this group, this call attaches to the existing invocation
+// instead of spawning a new goroutine, and every caller sees the same error on Wait.
+func (g *SyncGroup) Do(key string, fnc func() error) {
+	g.doInternal(key, fnc)
+}
+
+// TryDo is Do, except it also reports whether this call attached to an already in-flight
+// invocation for key (shared == true) instead of starting a new one, for observability.
+// This per-caller bool, rather than a running count on call itself, is the counter: callers
+// that want a rate of sharing (e.g. a metric) aggregate it themselves across their own calls,
+// the same way they would for any other per-call boolean outcome.
+func (g *SyncGroup) TryDo(key string, fnc func() error) (shared bool) {
+	return g.doInternal(key, fnc)
+}
+
+func (g *SyncGroup) doInternal(key string, fnc func() error) bool {
+	wg, errorChan := g.beginRound()
+
+	g.doMu.Lock()
+
+	if g.doCalls == nil {
+		g.doCalls = make(map[string]*call)
+	}
+
+	if c, ok := g.doCalls[key]; ok {
+		g.doMu.Unlock()
+
+		go g.awaitSharedCall(wg, errorChan, c)
+
+		return true
+	}
+
+	c := new(call)
+	c.wg.Add(1)
+	g.doCalls[key] = c
+	g.doMu.Unlock()
+
+	go g.runCall(key, wg, errorChan, c, fnc)
+
+	return false
+}
+
+// runCall executes fnc for the primary Do/TryDo call of a key and fans the result out to
+// every sharer waiting on c. It follows the same recover-then-reconstruct pattern as done,
+// plus the Goexit bookkeeping from x/sync/singleflight: if fnc never returns normally and
+// never panics, it must have called runtime.Goexit, which is let through undisturbed.
+func (g *SyncGroup) runCall(key string, wg *sync.WaitGroup, errorChan chan error, c *call, fnc func() error) {
+	normalReturn := false
+	recovered := false
+
+	defer func() {
+		if !normalReturn && !recovered {
+			c.err = errGoexit
+		}
+
+		// c.workerPanic must be set before c.wg.Done(), since sharers waiting on c.wg
+		// read it as soon as Wait() returns, with no other synchronization between them.
+		switch {
+		case c.err == nil:
+			// nothing to report
+		case errors.Is(c.err, errGoexit):
+			// let this goroutine keep unwinding via Goexit; nothing to report.
+		default:
+			if pe, ok := c.err.(*panicError); ok { //nolint:errorlint // panicError is never wrapped
+				c.workerPanic = newWorkerPanic(pe.value, pe.stack)
+			}
+		}
+
+		c.wg.Done()
+
+		g.doMu.Lock()
+		if g.doCalls[key] == c {
+			delete(g.doCalls, key)
+		}
+		g.doMu.Unlock()
+
+		switch {
+		case c.err == nil:
+		case errors.Is(c.err, errGoexit):
+		case c.workerPanic != nil:
+			errorChan <- c.workerPanic
+		default:
+			errorChan <- c.err
+		}
+
+		if g.semaphore != nil {
+			<-g.semaphore
+		}
+
+		wg.Done()
+	}()
+
+	// blocks until semaphore slot is acquired
+	if g.semaphore != nil {
+		g.semaphore <- semaphoreToken{}
+	}
+
+	func() {
+		defer func() {
+			if !normalReturn {
+				if msg := recover(); msg != nil {
+					c.err = &panicError{value: msg, stack: debug.Stack()}
+				}
+			}
+		}()
+
+		c.err = fnc()
+		normalReturn = true
+	}()
+
+	if !normalReturn {
+		recovered = true
+	}
+}
+
+// awaitSharedCall waits for the primary call of a shared key to finish and reports its
+// result to this sharer's own slot in the group: a plain error is reused as-is, a panic is
+// reported as a clone of the WorkerPanic the primary built, extended with this sharer's own
+// stack frame, and a runtime.Goexit is not propagated, since this sharer did not call it
+// itself. The clone matters because, unlike a panic unwinding through nested SyncGroups one
+// frame at a time, every sharer of a panicking call reads c.workerPanic concurrently, so
+// appending to its Stacktraces in place - as newWorkerPanic does for the nested case - would
+// race.
+func (g *SyncGroup) awaitSharedCall(wg *sync.WaitGroup, errorChan chan error, c *call) {
+	c.wg.Wait()
+
+	defer wg.Done()
+
+	switch {
+	case c.err == nil:
+	case errors.Is(c.err, errGoexit):
+	case c.workerPanic != nil:
+		errorChan <- clonedWorkerPanic(c.workerPanic, debug.Stack())
+	default:
+		errorChan <- c.err
+	}
+}
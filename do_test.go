@@ -0,0 +1,265 @@
+package syncgroup_test
+
+import (
+	"errors"
+	"runtime"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kurt212/syncgroup"
+	"github.com/kurt212/syncgroup/internal/testutil"
+)
+
+// collectWorkerPanics flattens the tree of errors.Join-ed errors that Wait returns,
+// collecting every WorkerPanic found along the way, since errors.As only ever returns the
+// first match and a shared panicking Do call reports one independent WorkerPanic per caller.
+func collectWorkerPanics(err error) []*syncgroup.WorkerPanic {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var result []*syncgroup.WorkerPanic
+
+		for _, sub := range joined.Unwrap() {
+			result = append(result, collectWorkerPanics(sub)...)
+		}
+
+		return result
+	}
+
+	var wp *syncgroup.WorkerPanic
+	if errors.As(err, &wp) {
+		return []*syncgroup.WorkerPanic{wp}
+	}
+
+	return nil
+}
+
+func TestDoDeduplicatesInFlightCalls(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.New()
+
+	startedChan := make(chan struct{})
+	releaseChan := make(chan struct{})
+
+	runCount := atomic.Int32{}
+
+	runnableFunc := func() error {
+		runCount.Add(1)
+		close(startedChan)
+		<-releaseChan
+
+		return nil
+	}
+
+	syncgrp.Do("key", runnableFunc)
+
+	<-startedChan
+
+	const sharersCount = 5
+
+	for range sharersCount {
+		syncgrp.Do("key", runnableFunc)
+	}
+
+	close(releaseChan)
+
+	err := syncgrp.Wait()
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	testutil.Equal(t, int32(1), runCount.Load())
+}
+
+func TestTryDoReportsShared(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.New()
+
+	releaseChan := make(chan struct{})
+
+	runnableFunc := func() error {
+		<-releaseChan
+
+		return nil
+	}
+
+	shared := syncgrp.TryDo("key", runnableFunc)
+	testutil.Equal(t, false, shared)
+
+	// the key is registered before TryDo returns; the primary call stays in flight
+	// until releaseChan is closed.
+	time.Sleep(10 * time.Millisecond)
+
+	shared = syncgrp.TryDo("key", runnableFunc)
+	testutil.Equal(t, true, shared)
+
+	close(releaseChan)
+
+	err := syncgrp.Wait()
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+}
+
+func TestDoSharesErrorWithAllCallers(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.New()
+
+	returnMyErr := MyError{"123"}
+
+	runnableFunc := func() error {
+		return returnMyErr
+	}
+
+	syncgrp.Do("key", runnableFunc)
+
+	err := syncgrp.Wait()
+
+	testutil.True(t, errors.Is(err, returnMyErr), "Result error should be found by errors.Is")
+}
+
+func TestDoSharesPanicWithAllCallers(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.New()
+
+	startedChan := make(chan struct{})
+	releaseChan := make(chan struct{})
+
+	syncgrp.Do("key", func() error {
+		close(startedChan)
+		<-releaseChan
+		panic("boom")
+	})
+
+	<-startedChan
+	syncgrp.Do("key", func() error {
+		return nil
+	})
+	close(releaseChan)
+
+	err := syncgrp.Wait()
+
+	testutil.True(
+		t,
+		errors.Is(err, syncgroup.ErrPanicRecovered),
+		"On panic should return special panic error",
+	)
+
+	// The primary call and its sharer each report their own WorkerPanic: the primary's
+	// carries only its own stack frame, the sharer's a clone extended with its own frame.
+	wps := collectWorkerPanics(err)
+	testutil.Equal(t, 2, len(wps))
+
+	stacktraceCounts := []int{len(wps[0].Stacktraces), len(wps[1].Stacktraces)}
+	sort.Ints(stacktraceCounts)
+
+	testutil.EqualSlices(t, []int{1, 2}, stacktraceCounts)
+}
+
+func TestDoSharesPanicWithMultipleConcurrentSharers(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.New()
+
+	startedChan := make(chan struct{})
+	releaseChan := make(chan struct{})
+
+	syncgrp.Do("key", func() error {
+		close(startedChan)
+		<-releaseChan
+		panic("boom")
+	})
+
+	<-startedChan
+
+	const sharersCount = 8
+
+	for range sharersCount {
+		syncgrp.Do("key", func() error {
+			return nil
+		})
+	}
+
+	close(releaseChan)
+
+	err := syncgrp.Wait()
+
+	testutil.True(
+		t,
+		errors.Is(err, syncgroup.ErrPanicRecovered),
+		"On panic should return special panic error",
+	)
+
+	// The primary reports the WorkerPanic it built and every sharer reports its own clone of it,
+	// none of them sharing a Stacktraces slice - exercised here under -race, since that's
+	// exactly the scenario where concurrent sharers would otherwise race appending to one.
+	wps := collectWorkerPanics(err)
+	testutil.Equal(t, sharersCount+1, len(wps))
+
+	for _, wp := range wps {
+		testutil.True(t, len(wp.Stacktraces) >= 1, "every WorkerPanic should carry at least its own frame")
+	}
+}
+
+func TestDoGoexitDoesNotPropagateToSharers(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.New()
+
+	startedChan := make(chan struct{})
+	releaseChan := make(chan struct{})
+
+	syncgrp.Do("key", func() error {
+		close(startedChan)
+		<-releaseChan
+		runtime.Goexit()
+
+		return nil
+	})
+
+	<-startedChan
+	syncgrp.Do("key", func() error {
+		return nil
+	})
+	close(releaseChan)
+
+	err := syncgrp.Wait()
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+}
+
+func TestDoAllowsRetryAfterPanic(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.New()
+
+	syncgrp.Do("key", func() error {
+		panic("boom")
+	})
+
+	// give the panicking call a chance to remove its entry from the key map.
+	time.Sleep(10 * time.Millisecond)
+
+	ranAgain := atomic.Bool{}
+
+	syncgrp.Do("key", func() error {
+		ranAgain.Store(true)
+
+		return nil
+	})
+
+	err := syncgrp.Wait()
+
+	testutil.True(
+		t,
+		errors.Is(err, syncgroup.ErrPanicRecovered),
+		"On panic should return special panic error",
+	)
+
+	testutil.True(t, ranAgain.Load(), "Do should allow retrying the key after a panic")
+}
@@ -0,0 +1,161 @@
+package syncgroup
+
+import (
+	"errors"
+	"runtime/debug"
+	"sync"
+)
+
+// typedCall tracks a single in-flight DoTyped invocation for a given key. workerPanic is set
+// by the primary call once, if fnc panicked, and then read - never mutated - by every sharer,
+// each of which reports its own clone extended with its own stack frame: the shared pointer
+// itself must stay read-only past that point, since multiple sharers read it concurrently.
+type typedCall[T any] struct {
+	wg          sync.WaitGroup
+	val         T
+	err         error
+	workerPanic *WorkerPanic
+}
+
+// DoTyped is the generic, value-returning sibling of SyncGroup.Do: if a task with key is
+// already running or queued in this group, this call attaches to the existing invocation
+// instead of spawning a new goroutine, and every caller's result slot receives the same
+// value and error. It follows the same panic and runtime.Goexit semantics as Do.
+// It panics if Wait has already been called once: Typed does not support the multi-round reuse
+// SyncGroup does, see the Typed doc comment.
+func (g *Typed[T]) DoTyped(key string, fnc func() (T, error)) {
+	g.mu.Lock()
+
+	if g.waited {
+		g.mu.Unlock()
+
+		panic("syncgroup: Typed does not support reuse across rounds, DoTyped called after Wait")
+	}
+
+	idx := len(g.results)
+
+	var zero T
+
+	g.results = append(g.results, zero)
+	g.mu.Unlock()
+
+	wg, errorChan := g.group.beginRound()
+
+	g.doMu.Lock()
+
+	if g.doCalls == nil {
+		g.doCalls = make(map[string]*typedCall[T])
+	}
+
+	if c, ok := g.doCalls[key]; ok {
+		g.doMu.Unlock()
+
+		go g.awaitTypedCall(wg, errorChan, idx, c)
+
+		return
+	}
+
+	c := new(typedCall[T])
+	c.wg.Add(1)
+	g.doCalls[key] = c
+	g.doMu.Unlock()
+
+	go g.runTypedCall(key, wg, errorChan, idx, c, fnc)
+}
+
+func (g *Typed[T]) runTypedCall(
+	key string,
+	wg *sync.WaitGroup,
+	errorChan chan error,
+	idx int,
+	c *typedCall[T],
+	fnc func() (T, error),
+) {
+	normalReturn := false
+	recovered := false
+
+	defer func() {
+		if !normalReturn && !recovered {
+			c.err = errGoexit
+		}
+
+		// c.workerPanic must be set before c.wg.Done(), since sharers waiting on c.wg
+		// read it as soon as Wait() returns, with no other synchronization between them.
+		if pe, ok := c.err.(*panicError); ok { //nolint:errorlint // panicError is never wrapped
+			c.workerPanic = newWorkerPanic(pe.value, pe.stack)
+		}
+
+		c.wg.Done()
+
+		g.doMu.Lock()
+		if g.doCalls[key] == c {
+			delete(g.doCalls, key)
+		}
+		g.doMu.Unlock()
+
+		switch {
+		case c.err == nil:
+			g.mu.Lock()
+			g.results[idx] = c.val
+			g.mu.Unlock()
+		case errors.Is(c.err, errGoexit):
+			// let this goroutine keep unwinding via Goexit; nothing to report.
+		case c.workerPanic != nil:
+			errorChan <- c.workerPanic
+		default:
+			errorChan <- c.err
+		}
+
+		wg.Done()
+
+		if g.group.semaphore != nil {
+			<-g.group.semaphore
+		}
+	}()
+
+	// blocks until semaphore slot is acquired
+	if g.group.semaphore != nil {
+		g.group.semaphore <- semaphoreToken{}
+	}
+
+	func() {
+		defer func() {
+			if !normalReturn {
+				if msg := recover(); msg != nil {
+					c.err = &panicError{value: msg, stack: debug.Stack()}
+				}
+			}
+		}()
+
+		c.val, c.err = fnc()
+		normalReturn = true
+	}()
+
+	if !normalReturn {
+		recovered = true
+	}
+}
+
+// awaitTypedCall waits for the primary call of a shared key to finish and reports its result
+// to this sharer's own slot in the group: a plain error or value is reused as-is, a panic is
+// reported as a clone of the WorkerPanic the primary built, extended with this sharer's own
+// stack frame, and a runtime.Goexit is not propagated, since this sharer did not call it
+// itself. The clone matters because every sharer of a panicking call reads c.workerPanic
+// concurrently, so appending to its Stacktraces in place would race.
+func (g *Typed[T]) awaitTypedCall(wg *sync.WaitGroup, errorChan chan error, idx int, c *typedCall[T]) {
+	c.wg.Wait()
+
+	defer wg.Done()
+
+	switch {
+	case c.err == nil:
+		g.mu.Lock()
+		g.results[idx] = c.val
+		g.mu.Unlock()
+	case errors.Is(c.err, errGoexit):
+	case c.workerPanic != nil:
+		errorChan <- clonedWorkerPanic(c.workerPanic, debug.Stack())
+	default:
+		errorChan <- c.err
+	}
+}
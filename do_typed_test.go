@@ -0,0 +1,106 @@
+package syncgroup_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/kurt212/syncgroup"
+	"github.com/kurt212/syncgroup/internal/testutil"
+)
+
+func TestDoTypedDeduplicatesInFlightCalls(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.NewTyped[int]()
+
+	startedChan := make(chan struct{})
+	releaseChan := make(chan struct{})
+
+	runCount := atomic.Int32{}
+
+	runnableFunc := func() (int, error) {
+		runCount.Add(1)
+		close(startedChan)
+		<-releaseChan
+
+		return 42, nil
+	}
+
+	syncgrp.DoTyped("key", runnableFunc)
+
+	<-startedChan
+
+	const sharersCount = 5
+
+	for range sharersCount {
+		syncgrp.DoTyped("key", runnableFunc)
+	}
+
+	close(releaseChan)
+
+	results, err := syncgrp.Wait()
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	testutil.Equal(t, int32(1), runCount.Load())
+
+	expected := []int{42, 42, 42, 42, 42, 42}
+
+	testutil.EqualSlices(t, expected, results)
+}
+
+func TestDoTypedSharesPanicWithAllCallers(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.NewTyped[int]()
+
+	startedChan := make(chan struct{})
+	releaseChan := make(chan struct{})
+
+	syncgrp.DoTyped("key", func() (int, error) {
+		close(startedChan)
+		<-releaseChan
+		panic("boom")
+	})
+
+	<-startedChan
+	syncgrp.DoTyped("key", func() (int, error) {
+		return 0, nil
+	})
+	close(releaseChan)
+
+	results, err := syncgrp.Wait()
+
+	testutil.True(
+		t,
+		errors.Is(err, syncgroup.ErrPanicRecovered),
+		"On panic should return special panic error",
+	)
+
+	expected := []int{0, 0}
+
+	testutil.EqualSlices(t, expected, results)
+}
+
+func TestDoTypedPanicsAfterWait(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.NewTyped[int]()
+
+	syncgrp.DoTyped("key", func() (int, error) {
+		return 1, nil
+	})
+
+	_, err := syncgrp.Wait()
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	testutil.Panics(t, func() {
+		syncgrp.DoTyped("key", func() (int, error) {
+			return 2, nil
+		})
+	}, "DoTyped after Wait should panic, Typed does not support reuse across rounds")
+}
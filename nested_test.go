@@ -0,0 +1,47 @@
+package syncgroup_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kurt212/syncgroup"
+	"github.com/kurt212/syncgroup/internal/testutil"
+)
+
+func TestNestedGroupsAppendOneStacktracePerLevel(t *testing.T) {
+	t.Parallel()
+
+	outer := syncgroup.New()
+
+	outer.Go(func() error {
+		inner := syncgroup.New()
+
+		inner.Go(func() error {
+			panic("boom")
+		})
+
+		err := inner.Wait()
+
+		var wp *syncgroup.WorkerPanic
+
+		if !errors.As(err, &wp) {
+			panic(err)
+		}
+
+		panic(wp)
+	})
+
+	err := outer.Wait()
+
+	testutil.True(
+		t,
+		errors.Is(err, syncgroup.ErrPanicRecovered),
+		"On panic should return special panic error",
+	)
+
+	var wp *syncgroup.WorkerPanic
+
+	testutil.True(t, errors.As(err, &wp), "error should unwrap to a *WorkerPanic")
+
+	testutil.Equal(t, 2, len(wp.Stacktraces))
+}
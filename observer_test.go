@@ -0,0 +1,153 @@
+package syncgroup_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/kurt212/syncgroup"
+	"github.com/kurt212/syncgroup/internal/testutil"
+)
+
+func TestOnErrorIsCalledAsErrorsArrive(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.New()
+
+	var reported []error
+
+	syncgrp.OnError(func(err error) {
+		reported = append(reported, err)
+	})
+
+	returnMyErr := MyError{"123"}
+
+	syncgrp.Go(func() error {
+		return nil
+	})
+
+	syncgrp.Go(func() error {
+		return returnMyErr
+	})
+
+	err := syncgrp.Wait()
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	expected := []error{returnMyErr}
+
+	testutil.EqualSlices(t, expected, reported)
+}
+
+func TestOnErrorIsNotCalledForPanics(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.New()
+
+	onErrorCalls := atomic.Int32{}
+
+	syncgrp.OnError(func(_ error) {
+		onErrorCalls.Add(1)
+	})
+
+	syncgrp.Go(func() error {
+		panic("boom")
+	})
+
+	err := syncgrp.Wait()
+
+	testutil.True(
+		t,
+		errors.Is(err, syncgroup.ErrPanicRecovered),
+		"On panic should return special panic error",
+	)
+
+	testutil.Equal(t, int32(0), onErrorCalls.Load())
+}
+
+func TestOnPanicIsCalledWithWorkerPanic(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.New()
+
+	var reported *syncgroup.WorkerPanic
+
+	syncgrp.OnPanic(func(wp *syncgroup.WorkerPanic) {
+		reported = wp
+	})
+
+	syncgrp.Go(func() error {
+		panic("boom")
+	})
+
+	err := syncgrp.Wait()
+
+	testutil.True(
+		t,
+		errors.Is(err, syncgroup.ErrPanicRecovered),
+		"On panic should return special panic error",
+	)
+
+	testutil.True(t, reported != nil, "OnPanic should have been called")
+	testutil.Equal(t, "boom", reported.Panic)
+}
+
+func TestCanNotSetOnErrorAfterGo(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.New()
+
+	stopChan := make(chan struct{})
+
+	syncgrp.Go(func() error {
+		<-stopChan
+
+		return nil
+	})
+
+	defer func() {
+		msg := recover()
+		testutil.True(t, msg != nil, "OnError should panic after Go was called")
+
+		close(stopChan)
+
+		err := syncgrp.Wait()
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+	}()
+
+	syncgrp.OnError(func(_ error) {})
+}
+
+func TestOnPanicAllowedAgainAfterWait(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.New()
+
+	firstCalls := atomic.Int32{}
+	syncgrp.OnPanic(func(_ *syncgroup.WorkerPanic) {
+		firstCalls.Add(1)
+	})
+
+	syncgrp.Go(func() error {
+		panic("boom")
+	})
+
+	_ = syncgrp.Wait()
+
+	secondCalls := atomic.Int32{}
+	syncgrp.OnPanic(func(_ *syncgroup.WorkerPanic) {
+		secondCalls.Add(1)
+	})
+
+	syncgrp.Go(func() error {
+		panic("boom again")
+	})
+
+	_ = syncgrp.Wait()
+
+	testutil.Equal(t, int32(1), firstCalls.Load())
+	testutil.Equal(t, int32(1), secondCalls.Load())
+}
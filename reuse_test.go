@@ -0,0 +1,186 @@
+package syncgroup_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/kurt212/syncgroup"
+	"github.com/kurt212/syncgroup/internal/testutil"
+)
+
+func TestWaitAllowsReuseWithGo(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.New()
+
+	syncgrp.Go(func() error {
+		return nil
+	})
+
+	err := syncgrp.Wait()
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	returnMyErr := MyError{"123"}
+
+	syncgrp.Go(func() error {
+		return returnMyErr
+	})
+
+	err = syncgrp.Wait()
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	testutil.True(t, errors.Is(err, returnMyErr), "Result error should be found by errors.Is")
+}
+
+func TestResetDerivesFreshContextAfterCancellation(t *testing.T) {
+	t.Parallel()
+
+	syncgrp, ctx := syncgroup.NewWithContext(context.Background())
+	syncgrp.SetCancelPolicy(syncgroup.CancelOnFirstError())
+
+	firstCtx := ctx
+
+	returnMyErr := MyError{"123"}
+
+	syncgrp.GoCtx(func(_ context.Context) error {
+		return returnMyErr
+	})
+
+	err := syncgrp.Wait()
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	testutil.True(t, firstCtx.Err() != nil, "first round's context should be canceled")
+
+	syncgrp.Reset()
+
+	var secondCtx context.Context
+
+	secondCtxLiveChan := make(chan error, 1)
+
+	syncgrp.GoCtx(func(ctx context.Context) error {
+		secondCtx = ctx
+		secondCtxLiveChan <- ctx.Err()
+
+		return nil
+	})
+
+	testutil.True(t, <-secondCtxLiveChan == nil, "second round's context should not start out canceled")
+
+	err = syncgrp.Wait()
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	testutil.True(t, secondCtx != firstCtx, "second round should use a fresh context")
+}
+
+func TestResetPanicsWhileRoundInProgress(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.New()
+
+	startedChan := make(chan struct{})
+	releaseChan := make(chan struct{})
+
+	syncgrp.Go(func() error {
+		close(startedChan)
+		<-releaseChan
+
+		return nil
+	})
+
+	<-startedChan
+
+	defer func() {
+		msg := recover()
+		testutil.True(t, msg != nil, "Reset should panic while a round is in progress")
+
+		close(releaseChan)
+
+		err := syncgrp.Wait()
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+	}()
+
+	syncgrp.Reset()
+}
+
+// TestConcurrentRoundsDoNotRaceOrPanic drives the "long-lived worker" usage pattern the
+// reuse feature is meant for: one goroutine repeatedly finishes a round via Go+Wait while
+// another concurrently submits via TryGo, so a round can end and the next one can begin
+// while callers are racing to use the group. It must run clean under -race: the group's
+// WaitGroup and cancel func are per-round state, and every caller must see a matching,
+// never a mismatched, pair of them.
+func TestConcurrentRoundsDoNotRaceOrPanic(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.New()
+
+	const iterations = 200
+
+	var wg sync.WaitGroup
+
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		for range iterations {
+			syncgrp.Go(func() error {
+				return nil
+			})
+
+			_ = syncgrp.Wait()
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for range iterations {
+			syncgrp.TryGo(func() error {
+				return nil
+			})
+		}
+	}()
+
+	wg.Wait()
+
+	_ = syncgrp.Wait()
+}
+
+func TestSetLimitAllowedBetweenRounds(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.New()
+	syncgrp.SetLimit(1)
+
+	syncgrp.Go(func() error {
+		return nil
+	})
+
+	err := syncgrp.Wait()
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	syncgrp.SetLimit(2)
+
+	syncgrp.Go(func() error {
+		return nil
+	})
+
+	err = syncgrp.Wait()
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+}
@@ -3,16 +3,20 @@
 // The main idea is to have an ability to run independent tasks in separate goroutines which way return errors.
 // A user can wait until all goroutines finish running and collect all occurred errors.
 //
-// The design is similar to errgroup (https://godoc.org/golang.org/x/sync/errgroup),
-// but it does not cancel the context of the goroutines if any of them returns an error.
+// The design is similar to errgroup (https://godoc.org/golang.org/x/sync/errgroup).
+// By default it does not cancel the context of the goroutines if any of them returns an error,
+// but this behavior is opt-in via NewWithContext and SetCancelPolicy,
+// so a single group can support both the "collect all errors" and the errgroup-style
+// short-circuit-on-first-error workflows.
 package syncgroup
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"runtime/debug"
+	"strings"
 	"sync"
-	"sync/atomic"
 )
 
 // ErrPanicRecovered is a special error that is returned when a panic is recovered from a goroutine.
@@ -21,6 +25,87 @@ import (
 // If the panic value was an error, you can use errors.Unwrap(err) to get the original error.
 var ErrPanicRecovered = errors.New("recovered from panic")
 
+// WorkerPanic is the error saved by a SyncGroup when one of its tasks panics.
+// It gives programmatic access to the original panic value and to every stack frame
+// that observed the panic as it unwound, which matters when a panic crosses nested SyncGroups:
+// instead of wrapping it again, the outer group appends its own frame to Stacktraces.
+//
+// WorkerPanic implements Is so that errors.Is(err, ErrPanicRecovered) reports true,
+// and Unwrap so that errors.Is/errors.As can reach Err, the original panic value if it was an error.
+type WorkerPanic struct {
+	// Panic is the value passed to panic().
+	Panic any
+
+	// Stacktraces holds one stack trace per SyncGroup the panic unwound through, plus one for
+	// the Do/TryDo sharer that reported it, if any, in the order it was observed:
+	// Stacktraces[0] is where the panic was first recovered.
+	Stacktraces []string
+
+	// Err is the panic value if it was an error, and nil otherwise.
+	Err error
+}
+
+// Error implements the error interface.
+func (wp *WorkerPanic) Error() string {
+	var sb strings.Builder
+
+	sb.WriteString(ErrPanicRecovered.Error())
+	sb.WriteString(": ")
+	sb.WriteString(fmt.Sprint(wp.Panic))
+
+	for _, trace := range wp.Stacktraces {
+		sb.WriteString("\n")
+		sb.WriteString(trace)
+	}
+
+	return sb.String()
+}
+
+// Unwrap returns the panic value if it was an error, so that errors.Is and errors.As
+// can reach it through this WorkerPanic.
+func (wp *WorkerPanic) Unwrap() error {
+	return wp.Err
+}
+
+// Is reports whether target is ErrPanicRecovered, so that errors.Is(err, ErrPanicRecovered)
+// keeps working for panics recovered as a WorkerPanic.
+func (wp *WorkerPanic) Is(target error) bool {
+	return target == ErrPanicRecovered
+}
+
+// newWorkerPanic builds the WorkerPanic to report for a value just recovered from a panic,
+// together with the stack trace captured at the point of recovery. If msg is itself a
+// WorkerPanic - because the panic already unwound through a nested SyncGroup or a Do/TryDo
+// sharer - its Stacktraces is extended in place instead of being wrapped again.
+func newWorkerPanic(msg any, stack []byte) *WorkerPanic {
+	switch val := msg.(type) {
+	case *WorkerPanic:
+		val.Stacktraces = append(val.Stacktraces, string(stack))
+
+		return val
+	case error:
+		return &WorkerPanic{Panic: val, Stacktraces: []string{string(stack)}, Err: val}
+	default:
+		return &WorkerPanic{Panic: val, Stacktraces: []string{string(stack)}}
+	}
+}
+
+// clonedWorkerPanic returns a new WorkerPanic carrying wp's Panic and Err, with stack
+// appended to a copy of its Stacktraces, leaving wp itself untouched. Do/TryDo sharers use
+// this instead of newWorkerPanic's in-place append, since more than one sharer may be
+// extending the very same *WorkerPanic concurrently.
+func clonedWorkerPanic(wp *WorkerPanic, stack []byte) *WorkerPanic {
+	stacktraces := make([]string, len(wp.Stacktraces)+1)
+	copy(stacktraces, wp.Stacktraces)
+	stacktraces[len(wp.Stacktraces)] = string(stack)
+
+	return &WorkerPanic{
+		Panic:       wp.Panic,
+		Stacktraces: stacktraces,
+		Err:         wp.Err,
+	}
+}
+
 // SyncGroup is the main class for working with syncgroups. It's a collection of goroutines that can be waited for.
 // Additionally, SyncGroup collects all errors returned by goroutines,
 // handles panics and provides a way to limit the number of concurrent goroutines.
@@ -32,43 +117,133 @@ var ErrPanicRecovered = errors.New("recovered from panic")
 // Wait() waits until all spawned goroutines finish and returns a wrapper for a slice of errors.
 // If there was no error, Wait() would return nil,
 // otherwise a non nil error, which can be unwrapped to access all errors.
+//
+// A SyncGroup can be reused for multiple rounds: once Wait() returns, calling Go (or TryGo,
+// GoCtx, TryGoCtx, Do, TryDo) again starts a fresh round with its own listening goroutine,
+// without allocating a new SyncGroup. SetLimit and SetCancelPolicy may be changed between rounds.
 type SyncGroup struct {
-	wg        sync.WaitGroup
 	semaphore chan semaphoreToken
 
-	finishedChan chan []error
-	errorChan    chan error
-
-	listeningStarted        atomic.Bool
-	listeningRoutineStarter *sync.Once
+	// mu guards listening, draining, wg, roundFinished, finishedChan, errorChan, ctx, cancel
+	// and needsFreshContext below, which together make up the per-round state that beginRound
+	// and Reset prepare anew for each round. wg is a pointer, recreated per round same as the
+	// channels, so a round's own goroutines keep Add-ing to and Done-ing the WaitGroup they
+	// started with, even if a later round has already replaced g.wg by the time they read it.
+	//
+	// draining marks that Wait has committed to this round's wg.Wait() call: beginRound must
+	// never Add to a wg that may already be draining, since sync.WaitGroup only allows a
+	// positive Add to race with Wait while the counter is known to stay above zero, which
+	// beginRound cannot guarantee once Wait has started watching it. So once draining is set,
+	// beginRound blocks new callers on roundFinished instead of joining the current round,
+	// and they start the next round once Wait has fully torn this one down.
+	mu        sync.Mutex
+	listening bool
+	draining  bool
+
+	wg            *sync.WaitGroup
+	roundFinished chan struct{}
+	finishedChan  chan []error
+	errorChan     chan error
+
+	parentCtx         context.Context
+	ctx               context.Context
+	cancel            context.CancelCauseFunc
+	needsFreshContext bool
+
+	cancelPolicy CancelPolicy
+
+	onError func(err error)
+	onPanic func(wp *WorkerPanic)
+
+	doMu    sync.Mutex
+	doCalls map[string]*call
 }
 
 type semaphoreToken struct{}
 
+// CancelPolicy decides whether a SyncGroup should cancel its context in reaction
+// to errors or panics returned by its tasks. Policies are created with the
+// CancelNever, CancelOnFirstError, CancelOnFirstPanic and CancelOnErrorCount
+// constructors and installed with SetCancelPolicy.
+type CancelPolicy struct {
+	shouldCancel func(errCount int, isPanic bool) bool
+}
+
+// CancelNever never cancels the group's context. This is the default policy and
+// preserves the "collect all errors" behavior of a group created without one.
+func CancelNever() CancelPolicy {
+	return CancelPolicy{
+		shouldCancel: func(_ int, _ bool) bool {
+			return false
+		},
+	}
+}
+
+// CancelOnFirstError cancels the group's context as soon as any task returns an error or panics.
+// This mirrors errgroup's default short-circuit behavior.
+func CancelOnFirstError() CancelPolicy {
+	return CancelPolicy{
+		shouldCancel: func(errCount int, _ bool) bool {
+			return errCount > 0
+		},
+	}
+}
+
+// CancelOnFirstPanic cancels the group's context as soon as any task panics, but not on plain errors.
+func CancelOnFirstPanic() CancelPolicy {
+	return CancelPolicy{
+		shouldCancel: func(_ int, isPanic bool) bool {
+			return isPanic
+		},
+	}
+}
+
+// CancelOnErrorCount cancels the group's context once n tasks have returned an error or panicked.
+func CancelOnErrorCount(n int) CancelPolicy {
+	return CancelPolicy{
+		shouldCancel: func(errCount int, _ bool) bool {
+			return errCount >= n
+		},
+	}
+}
+
 // New is the default constructor for SyncGroup.
 func New() *SyncGroup {
+	parentCtx := context.Background()
+	ctx, cancel := context.WithCancelCause(parentCtx)
+
 	grp := &SyncGroup{
-		wg:                      sync.WaitGroup{},
-		semaphore:               nil,
-		finishedChan:            make(chan []error),
-		errorChan:               make(chan error),
-		listeningStarted:        atomic.Bool{},
-		listeningRoutineStarter: new(sync.Once),
+		wg:           new(sync.WaitGroup),
+		semaphore:    nil,
+		parentCtx:    parentCtx,
+		ctx:          ctx,
+		cancel:       cancel,
+		cancelPolicy: CancelNever(),
 	}
 
 	return grp
 }
 
+// NewWithContext returns a new SyncGroup and a context derived from ctx.
+// The derived context is canceled the first time a task satisfies the group's CancelPolicy,
+// or when Wait returns, whichever happens first.
+// The default policy is CancelNever; use SetCancelPolicy to opt into errgroup-style cancellation.
+func NewWithContext(ctx context.Context) (*SyncGroup, context.Context) {
+	grp := New()
+	grp.parentCtx = ctx
+	grp.ctx, grp.cancel = context.WithCancelCause(ctx)
+
+	return grp, grp.ctx
+}
+
 // Go spawns given function in a new goroutine.
 // If group has a limit of concurrent goroutines, goroutine execution will be blocked until a slot is available.
 // The returned error will be saved and returned wrapped by Wait() method.
 func (g *SyncGroup) Go(fnc func() error) {
-	g.startListening()
-
-	g.wg.Add(1)
+	wg, errorChan := g.beginRound()
 
 	go func() {
-		defer g.done()
+		defer g.done(wg, errorChan)
 
 		// blocks until semaphore slot is acquired
 		if g.semaphore != nil {
@@ -77,7 +252,7 @@ func (g *SyncGroup) Go(fnc func() error) {
 
 		err := fnc()
 		if err != nil {
-			g.errorChan <- err
+			errorChan <- err
 		}
 	}()
 }
@@ -91,63 +266,187 @@ func (g *SyncGroup) TryGo(fnc func() error) bool {
 		}
 	}
 
-	g.startListening()
-	g.wg.Add(1)
+	wg, errorChan := g.beginRound()
 
 	go func() {
-		defer g.done()
+		defer g.done(wg, errorChan)
 
 		err := fnc()
 		if err != nil {
-			g.errorChan <- err
+			errorChan <- err
 		}
 	}()
 
 	return true
 }
 
-// done is called in every goroutine spawned by SyncGroup in defer statement.
-// Its job is to handle panics, release all resources and decrement the WaitGroup counter.
-func (g *SyncGroup) done() {
-	if msg := recover(); msg != nil {
-		var err error
+// GoCtx spawns given function in a new goroutine, passing it the group's context.
+// In the first round the context is the one returned by NewWithContext (or an internal
+// background context for a group created with New); every later round gets a fresh context
+// derived from the same parent. It is canceled according to the group's CancelPolicy.
+// If group has a limit of concurrent goroutines, goroutine execution will be blocked until a slot is available.
+// The returned error will be saved and returned wrapped by Wait() method.
+func (g *SyncGroup) GoCtx(fnc func(ctx context.Context) error) {
+	wg, errorChan := g.beginRound()
 
-		switch val := msg.(type) {
-		case error:
-			err = fmt.Errorf("%w: %w\n%s", ErrPanicRecovered, val, string(debug.Stack()))
+	go func() {
+		defer g.done(wg, errorChan)
+
+		// blocks until semaphore slot is acquired
+		if g.semaphore != nil {
+			g.semaphore <- semaphoreToken{}
+		}
+
+		err := fnc(g.ctx)
+		if err != nil {
+			errorChan <- err
+		}
+	}()
+}
+
+// TryGoCtx is the non-blocking, context-aware counterpart of TryGo. See GoCtx for the semantics
+// of the context passed to fnc.
+func (g *SyncGroup) TryGoCtx(fnc func(ctx context.Context) error) bool {
+	if g.semaphore != nil {
+		select {
+		case g.semaphore <- semaphoreToken{}:
 		default:
-			err = fmt.Errorf("%w: %v\n%s", ErrPanicRecovered, val, string(debug.Stack()))
+			return false
 		}
+	}
+
+	wg, errorChan := g.beginRound()
+
+	go func() {
+		defer g.done(wg, errorChan)
+
+		err := fnc(g.ctx)
+		if err != nil {
+			errorChan <- err
+		}
+	}()
 
-		g.errorChan <- err
+	return true
+}
+
+// done is called in every goroutine spawned by SyncGroup in defer statement.
+// Its job is to handle panics, release all resources and decrement the WaitGroup counter.
+// It takes this round's wg and errorChan explicitly, the same way listenToErrors does, since
+// by the time it runs, a later round may already have replaced g.wg/g.errorChan with new ones.
+func (g *SyncGroup) done(wg *sync.WaitGroup, errorChan chan error) {
+	if msg := recover(); msg != nil {
+		errorChan <- newWorkerPanic(msg, debug.Stack())
 	}
 
 	if g.semaphore != nil {
 		<-g.semaphore
 	}
 
-	g.wg.Done()
+	wg.Done()
+}
+
+// beginRound joins the in-progress round, or starts a new one if none is in progress or the
+// current one is draining: it prepares a fresh context (for every round after the first), a
+// fresh WaitGroup and pair of channels, and spawns listenToErrors for them. It adds 1 to the
+// round's WaitGroup before returning it, while still holding mu, so that this Add can never
+// race with the same round's Wait call - see the mu doc comment above.
+// It returns this round's WaitGroup and error channel, snapshotted together under mu so
+// that a concurrent call always gets a matching pair, never a mix of an old and a new round.
+func (g *SyncGroup) beginRound() (*sync.WaitGroup, chan error) {
+	g.mu.Lock()
+
+	for g.listening && g.draining {
+		roundFinished := g.roundFinished
+
+		g.mu.Unlock()
+		<-roundFinished
+		g.mu.Lock()
+	}
+
+	if !g.listening {
+		g.prepareRoundLocked()
+
+		g.listening = true
+		g.wg = new(sync.WaitGroup)
+		g.roundFinished = make(chan struct{})
+		g.finishedChan = make(chan []error)
+		g.errorChan = make(chan error)
+
+		go g.listenToErrors(g.errorChan, g.finishedChan)
+	}
+
+	g.wg.Add(1)
+
+	wg := g.wg
+	errorChan := g.errorChan
+
+	g.mu.Unlock()
+
+	return wg, errorChan
+}
+
+// prepareRoundLocked derives a fresh ctx/cancel pair from parentCtx if the previous round
+// left its context canceled. It must be called with mu held.
+func (g *SyncGroup) prepareRoundLocked() {
+	if g.needsFreshContext {
+		g.ctx, g.cancel = context.WithCancelCause(g.parentCtx)
+		g.needsFreshContext = false
+	}
 }
 
-func (g *SyncGroup) startListening() {
-	g.listeningRoutineStarter.Do(func() {
-		g.listeningStarted.Store(true)
-		go g.listenToErrors()
-	})
+// Reset explicitly prepares the group for a new round, the same way the next call to Go,
+// TryGo, GoCtx, TryGoCtx, Do or TryDo would automatically: if the previous round left the
+// group's context canceled, it derives a fresh one before the round's first task is spawned.
+// That context is not returned; tasks receive it through GoCtx and TryGoCtx.
+// It panics if a round is currently in progress.
+func (g *SyncGroup) Reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.listening {
+		panic("cannot reset syncgroup while a round is in progress")
+	}
+
+	g.prepareRoundLocked()
 }
 
-// listenToErrors is a single per group goroutine that listens to all errors and accumulates them.
-func (g *SyncGroup) listenToErrors() {
+// listenToErrors is the single goroutine per round that listens to all errors and accumulates
+// them. It also applies the group's CancelPolicy, canceling the group's context, with the
+// triggering error as the cause, as soon as the policy is satisfied.
+// It takes this round's channels explicitly, since by the time it reads them again via g,
+// a later round may already have replaced g.errorChan/g.finishedChan with new ones.
+func (g *SyncGroup) listenToErrors(errorChan chan error, finishedChan chan []error) {
 	defer func() {
-		close(g.finishedChan)
+		close(finishedChan)
 	}()
 
 	var accumulatedErrors []error //nolint:prealloc // false positive
-	for err := range g.errorChan {
+
+	errCount := 0
+
+	for err := range errorChan {
 		accumulatedErrors = append(accumulatedErrors, err)
+		errCount++
+
+		isPanic := errors.Is(err, ErrPanicRecovered)
+
+		switch {
+		case isPanic && g.onPanic != nil:
+			var wp *WorkerPanic
+
+			if errors.As(err, &wp) {
+				g.onPanic(wp)
+			}
+		case !isPanic && g.onError != nil:
+			g.onError(err)
+		}
+
+		if g.cancelPolicy.shouldCancel(errCount, isPanic) {
+			g.cancel(err)
+		}
 	}
 
-	g.finishedChan <- accumulatedErrors
+	finishedChan <- accumulatedErrors
 }
 
 // Wait waits until all spawned goroutines are finished and returns a wrapped error for all collected errors.
@@ -155,25 +454,69 @@ func (g *SyncGroup) listenToErrors() {
 //
 // If error is not nil, the result is guaranteed to implement `Unwrap() []errors` methods to access all errors.
 // The error supports unwrapping with standard errors.Unwrap(), errors.Is() and errors.As() functions.
+//
+// Wait also cancels the group's context (the one returned by NewWithContext), with the returned
+// error as the cause, so the context does not leak past the group's lifetime.
+//
+// Once Wait returns, the group is ready for a new round: calling Go (or TryGo, GoCtx, TryGoCtx,
+// Do, TryDo) again starts a fresh round, with its own listening goroutine and a fresh context
+// derived from the one the group (or NewWithContext) was created with.
 func (g *SyncGroup) Wait() error {
-	if !g.listeningStarted.Load() {
+	g.mu.Lock()
+
+	if !g.listening {
+		g.mu.Unlock()
+
 		return nil
 	}
 
-	g.wg.Wait()
-	close(g.errorChan)
+	wg := g.wg
+	errorChan := g.errorChan
+	finishedChan := g.finishedChan
+	cancel := g.cancel
+	roundFinished := g.roundFinished
+
+	// Marking the round as draining in the same critical section that snapshots wg prevents
+	// a concurrent beginRound from Add-ing to it afterwards: it will see draining and wait for
+	// roundFinished instead, so wg.Wait() below never races with a late Add on this same round.
+	g.draining = true
 
-	errs := <-g.finishedChan
+	g.mu.Unlock()
+
+	wg.Wait()
+	close(errorChan)
+
+	errs := <-finishedChan
+
+	g.mu.Lock()
+	g.listening = false
+	g.draining = false
+	g.needsFreshContext = true
+	close(roundFinished)
+	g.mu.Unlock()
 
 	if len(errs) == 0 {
+		cancel(nil)
+
 		return nil
 	}
 
-	return errors.Join(errs...)
+	err := errors.Join(errs...)
+
+	cancel(err)
+
+	return err
 }
 
+// SetLimit limits the number of concurrently running goroutines. It must be called before the
+// first call to Go, TryGo, GoCtx, TryGoCtx, Do or TryDo in a round, but may be changed again in
+// any later round, once Wait has returned.
 func (g *SyncGroup) SetLimit(limit int) {
-	if g.listeningStarted.Load() {
+	g.mu.Lock()
+	listening := g.listening
+	g.mu.Unlock()
+
+	if listening {
 		panic("cannot set limit after starting goroutines")
 	}
 
@@ -185,3 +528,53 @@ func (g *SyncGroup) SetLimit(limit int) {
 
 	g.semaphore = make(chan semaphoreToken, limit)
 }
+
+// SetCancelPolicy configures when the group should cancel its context in reaction to
+// task errors or panics. It must be called before the first call to Go, TryGo, GoCtx, TryGoCtx,
+// Do or TryDo in a round, mirroring SetLimit, but may be changed again in any later round,
+// once Wait has returned.
+// The default policy, used if SetCancelPolicy is never called, is CancelNever.
+func (g *SyncGroup) SetCancelPolicy(policy CancelPolicy) {
+	g.mu.Lock()
+	listening := g.listening
+	g.mu.Unlock()
+
+	if listening {
+		panic("cannot set cancel policy after starting goroutines")
+	}
+
+	g.cancelPolicy = policy
+}
+
+// OnError registers a callback invoked from the listening goroutine as soon as a task
+// returns a plain (non-panic) error, before Wait returns. It is meant for progress
+// reporting, metrics or early logging in long-running batch jobs, and does not affect
+// the errors collected and returned by Wait. It must be called before the first call to
+// Go, TryGo, GoCtx, TryGoCtx, Do or TryDo in a round, mirroring SetLimit, but may be
+// changed again in any later round, once Wait has returned.
+func (g *SyncGroup) OnError(cb func(err error)) {
+	g.mu.Lock()
+	listening := g.listening
+	g.mu.Unlock()
+
+	if listening {
+		panic("cannot set error callback after starting goroutines")
+	}
+
+	g.onError = cb
+}
+
+// OnPanic registers a callback invoked from the listening goroutine as soon as a task
+// panics, before Wait returns. It is the panic counterpart of OnError, and follows the
+// same registration rules.
+func (g *SyncGroup) OnPanic(cb func(wp *WorkerPanic)) {
+	g.mu.Lock()
+	listening := g.listening
+	g.mu.Unlock()
+
+	if listening {
+		panic("cannot set panic callback after starting goroutines")
+	}
+
+	g.onPanic = cb
+}
@@ -19,7 +19,7 @@ func TestListenTo(t *testing.T) {
 
 	syncgrp := New()
 
-	syncgrp.startListening()
+	syncgrp.beginRound()
 
 	syncgrp.errorChan <- MyError{"err1"}
 	syncgrp.errorChan <- MyError{"err2"}
@@ -0,0 +1,91 @@
+package syncgroup
+
+import "sync"
+
+// Typed is a generic sibling of SyncGroup for fan-out/fan-in workloads where each task
+// produces a value of type T in addition to a possible error. It reuses a SyncGroup
+// internally for scheduling, limiting and error collection, so it supports the same
+// panic handling and (via SetLimit) concurrency limiting as SyncGroup.
+//
+// Unlike SyncGroup, a Typed does not support being reused for another round once Wait has
+// been called: results is a single slice indexed by submission order across the whole
+// lifetime of the group, so a second round would either corrupt it or require resetting it
+// in a way that cannot be made safe against a Go/DoTyped racing a concurrent Wait. Go and
+// DoTyped panic if called after Wait has already been called once.
+type Typed[T any] struct {
+	group *SyncGroup
+
+	mu      sync.Mutex
+	results []T
+	waited  bool
+
+	doMu    sync.Mutex
+	doCalls map[string]*typedCall[T]
+}
+
+// NewTyped is the default constructor for Typed.
+func NewTyped[T any]() *Typed[T] {
+	return &Typed[T]{
+		group: New(),
+	}
+}
+
+// Go spawns given function in a new goroutine.
+// If group has a limit of concurrent goroutines, goroutine execution will be blocked until a slot is available.
+// The returned value will be stored at the index matching submission order and returned by Wait(),
+// regardless of how many tasks finish before it. The returned error will be saved and returned
+// wrapped by Wait(), same as SyncGroup.Go.
+// It panics if Wait has already been called once: Typed does not support the multi-round reuse
+// SyncGroup does, see the Typed doc comment.
+func (g *Typed[T]) Go(fnc func() (T, error)) {
+	g.mu.Lock()
+
+	if g.waited {
+		g.mu.Unlock()
+
+		panic("syncgroup: Typed does not support reuse across rounds, Go called after Wait")
+	}
+
+	idx := len(g.results)
+
+	var zero T
+
+	g.results = append(g.results, zero)
+	g.mu.Unlock()
+
+	g.group.Go(func() error {
+		val, err := fnc()
+		if err != nil {
+			return err
+		}
+
+		g.mu.Lock()
+		g.results[idx] = val
+		g.mu.Unlock()
+
+		return nil
+	})
+}
+
+// SetLimit limits the number of concurrently running goroutines, same as SyncGroup.SetLimit.
+func (g *Typed[T]) SetLimit(limit int) {
+	g.group.SetLimit(limit)
+}
+
+// Wait waits until all spawned goroutines are finished and returns the collected results,
+// in submission order, alongside a wrapped error for all collected errors, same as SyncGroup.Wait.
+// A task whose fnc panicked or returned an error leaves its result slot at the zero value of T,
+// without shifting the indices of any other task's result.
+// Once Wait has been called, this Typed is retired: further calls to Go or DoTyped panic.
+func (g *Typed[T]) Wait() ([]T, error) {
+	g.mu.Lock()
+	g.waited = true
+	g.mu.Unlock()
+
+	err := g.group.Wait()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.results, err
+}
@@ -0,0 +1,115 @@
+package syncgroup_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kurt212/syncgroup"
+	"github.com/kurt212/syncgroup/internal/testutil"
+)
+
+func TestTypedGoCollectsResultsInOrder(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.NewTyped[int]()
+
+	const count = 5
+
+	for i := range count {
+		syncgrp.Go(func() (int, error) {
+			return i * i, nil
+		})
+	}
+
+	results, err := syncgrp.Wait()
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	expected := []int{0, 1, 4, 9, 16}
+
+	testutil.EqualSlices(t, expected, results)
+}
+
+func TestTypedGoWithError(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.NewTyped[int]()
+
+	returnMyErr := MyError{"123"}
+
+	syncgrp.Go(func() (int, error) {
+		return 1, nil
+	})
+
+	syncgrp.Go(func() (int, error) {
+		return 0, returnMyErr
+	})
+
+	syncgrp.Go(func() (int, error) {
+		return 2, nil
+	})
+
+	results, err := syncgrp.Wait()
+
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	testutil.True(t, errors.Is(err, returnMyErr), "Result error should be found by errors.Is")
+
+	expected := []int{1, 0, 2}
+
+	testutil.EqualSlices(t, expected, results)
+}
+
+func TestTypedGoRecoversPanicWithoutShiftingIndices(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.NewTyped[int]()
+
+	syncgrp.Go(func() (int, error) {
+		return 1, nil
+	})
+
+	syncgrp.Go(func() (int, error) {
+		panic("boom")
+	})
+
+	syncgrp.Go(func() (int, error) {
+		return 3, nil
+	})
+
+	results, err := syncgrp.Wait()
+
+	testutil.True(
+		t,
+		errors.Is(err, syncgroup.ErrPanicRecovered),
+		"On panic should return special panic error",
+	)
+
+	expected := []int{1, 0, 3}
+
+	testutil.EqualSlices(t, expected, results)
+}
+
+func TestTypedGoPanicsAfterWait(t *testing.T) {
+	t.Parallel()
+
+	syncgrp := syncgroup.NewTyped[int]()
+
+	syncgrp.Go(func() (int, error) {
+		return 1, nil
+	})
+
+	_, err := syncgrp.Wait()
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	testutil.Panics(t, func() {
+		syncgrp.Go(func() (int, error) {
+			return 2, nil
+		})
+	}, "Go after Wait should panic, Typed does not support reuse across rounds")
+}